@@ -0,0 +1,136 @@
+package postal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Attachment represents a file attached to an outgoing message.
+type Attachment struct {
+	// Name is the attachment's file name.
+	Name string `json:"name"`
+	// ContentType is the MIME type of the attachment's Data.
+	ContentType string `json:"content_type"`
+	// Data is the raw, unencoded contents of the attachment.
+	Data []byte `json:"data"`
+	// Inline marks the attachment for inline display, e.g. an image referenced
+	// from the HTML body via its ContentID. Not part of the Postal API's JSON
+	// attachment shape, so it is excluded from Marshal/UnmarshalJSON.
+	Inline bool `json:"-"`
+	// ContentID is the Content-ID an inline attachment is referenced by from the
+	// HTML body (e.g. <img src="cid:ContentID">). Only meaningful when Inline is
+	// true, and likewise excluded from Marshal/UnmarshalJSON.
+	ContentID string `json:"-"`
+}
+
+// mimeTypesByExt supplements mime.TypeByExtension and http.DetectContentType for
+// common extensions they don't reliably resolve.
+var mimeTypesByExt = map[string]string{
+	".csv":  "text/csv",
+	".json": "application/json",
+	".pdf":  "application/pdf",
+	".txt":  "text/plain",
+}
+
+// NewAttachmentFromFile reads the file at path and builds an Attachment from its
+// contents, detecting its content type from its name and, failing that, its content.
+func NewAttachmentFromFile(path string) (*Attachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewAttachmentFromReader(filepath.Base(path), f)
+}
+
+// NewAttachmentFromReader reads r to completion and builds an Attachment named
+// name from its contents, detecting its content type from name's extension and,
+// failing that, its content.
+func NewAttachmentFromReader(name string, r io.Reader) (*Attachment, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attachment{
+		Name:        name,
+		ContentType: detectContentType(name, data),
+		Data:        data,
+	}, nil
+}
+
+// NewInlineAttachment builds an inline Attachment, such as an image embedded in an
+// HTML body via <img src="cid:contentID">.
+func NewInlineAttachment(name, contentID string, data []byte) *Attachment {
+	return &Attachment{
+		Name:        name,
+		ContentType: detectContentType(name, data),
+		Data:        data,
+		Inline:      true,
+		ContentID:   contentID,
+	}
+}
+
+func detectContentType(name string, data []byte) string {
+	if ext := filepath.Ext(name); ext != "" {
+		if ct, ok := mimeTypesByExt[ext]; ok {
+			return ct
+		}
+		if ct := mime.TypeByExtension(ext); ct != "" {
+			return ct
+		}
+	}
+	return http.DetectContentType(data)
+}
+
+// Validate reports an AttachmentMissingNameError if the attachment has no name, as
+// required by the Postal API.
+func (a *Attachment) Validate() error {
+	if a.Name == "" {
+		return &AttachmentMissingNameError{&APIError{Message: "attachment is missing a name"}}
+	}
+	return nil
+}
+
+// attachmentJSON is the wire shape the Postal API expects for an attachment.
+type attachmentJSON struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        string `json:"data"`
+}
+
+// MarshalJSON implements json.Marshaler, base64-encoding Data into the
+// {name, content_type, data} shape the Postal API expects.
+func (a *Attachment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(attachmentJSON{
+		Name:        a.Name,
+		ContentType: a.ContentType,
+		Data:        base64.StdEncoding.EncodeToString(a.Data),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, the inverse of MarshalJSON, decoding
+// the base64 data field back into Data.
+func (a *Attachment) UnmarshalJSON(data []byte) error {
+	var wire attachmentJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(wire.Data)
+	if err != nil {
+		return fmt.Errorf("postal: decoding attachment data: %w", err)
+	}
+
+	a.Name = wire.Name
+	a.ContentType = wire.ContentType
+	a.Data = decoded
+	return nil
+}
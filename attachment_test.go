@@ -0,0 +1,30 @@
+package postal
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAttachmentJSONRoundTrip(t *testing.T) {
+	want := &Attachment{Name: "report.csv", ContentType: "text/csv", Data: []byte("a,b,c")}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got Attachment
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Name != want.Name {
+		t.Errorf("Name = %q, want %q", got.Name, want.Name)
+	}
+	if got.ContentType != want.ContentType {
+		t.Errorf("ContentType = %q, want %q", got.ContentType, want.ContentType)
+	}
+	if string(got.Data) != string(want.Data) {
+		t.Errorf("Data = %q, want %q", got.Data, want.Data)
+	}
+}
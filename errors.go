@@ -0,0 +1,93 @@
+package postal
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// APIError is the common error shape every typed error below wraps. It carries the
+// Postal-assigned error code and message alongside the raw HTTP status and response
+// body, for callers that need more than the typed error's Error() string.
+type APIError struct {
+	// Code is the value of data.code in the Postal response, e.g. "ValidationError".
+	Code string
+	// Message is the value of data.message in the Postal response.
+	Message string
+	// HTTPStatus is the HTTP status code of the response.
+	HTTPStatus int
+	// Data is the raw, decoded data field of the Postal response.
+	Data interface{}
+	// Response is the raw HTTP response that produced this error.
+	Response *http.Response
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	if e.Response != nil && e.Response.Request != nil {
+		return fmt.Sprintf("%v %v: %d %s: %s",
+			e.Response.Request.Method, e.Response.Request.URL, e.HTTPStatus, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%d %s: %s", e.HTTPStatus, e.Code, e.Message)
+}
+
+// ValidationError is returned when the Postal API rejects a request's parameters.
+type ValidationError struct{ *APIError }
+
+// AccessDeniedError is returned when the API key does not have access to the requested resource.
+type AccessDeniedError struct{ *APIError }
+
+// RateLimitError is returned when the caller has exceeded Postal's rate limits.
+type RateLimitError struct{ *APIError }
+
+// InvalidServerAPIKeyError is returned when the configured API key is not valid.
+type InvalidServerAPIKeyError struct{ *APIError }
+
+// MessageNotFoundError is returned when a requested message ID does not exist.
+type MessageNotFoundError struct{ *APIError }
+
+// UnableToSendMessageError is returned when Postal could not queue a message for sending.
+type UnableToSendMessageError struct{ *APIError }
+
+// NoContentError is returned when a send request has neither a plain nor HTML body.
+type NoContentError struct{ *APIError }
+
+// NoRecipientsError is returned when a send request has no recipients.
+type NoRecipientsError struct{ *APIError }
+
+// AttachmentMissingNameError is returned when a send request includes an attachment without a name.
+type AttachmentMissingNameError struct{ *APIError }
+
+// errorCodeTypes maps the Postal data.code values this client recognizes to their
+// concrete, typed error.
+var errorCodeTypes = map[string]func(*APIError) error{
+	"ValidationError":       func(e *APIError) error { return &ValidationError{e} },
+	"AccessDenied":          func(e *APIError) error { return &AccessDeniedError{e} },
+	"RateLimited":           func(e *APIError) error { return &RateLimitError{e} },
+	"InvalidServerAPIKey":   func(e *APIError) error { return &InvalidServerAPIKeyError{e} },
+	"MessageNotFound":       func(e *APIError) error { return &MessageNotFoundError{e} },
+	"UnableToSendMessage":   func(e *APIError) error { return &UnableToSendMessageError{e} },
+	"NoContent":             func(e *APIError) error { return &NoContentError{e} },
+	"NoRecipients":          func(e *APIError) error { return &NoRecipientsError{e} },
+	"AttachmentMissingName": func(e *APIError) error { return &AttachmentMissingNameError{e} },
+}
+
+// newTypedError builds the concrete error for a non-success Postal response, falling
+// back to a RateLimitError for a bare HTTP 429 and to *APIError itself when the code
+// is unrecognized.
+func newTypedError(r *http.Response, data map[string]interface{}) error {
+	apiErr := &APIError{HTTPStatus: r.StatusCode, Response: r, Data: data}
+	if code, ok := data["code"].(string); ok {
+		apiErr.Code = code
+	}
+	if msg, ok := data["message"].(string); ok {
+		apiErr.Message = msg
+	}
+
+	if ctor, ok := errorCodeTypes[apiErr.Code]; ok {
+		return ctor(apiErr)
+	}
+	if r.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{apiErr}
+	}
+	return apiErr
+}
@@ -0,0 +1,88 @@
+package postal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewTypedError(t *testing.T) {
+	tests := []struct {
+		code       string
+		httpStatus int
+		want       error
+	}{
+		{"ValidationError", http.StatusUnprocessableEntity, &ValidationError{}},
+		{"AccessDenied", http.StatusForbidden, &AccessDeniedError{}},
+		{"RateLimited", http.StatusTooManyRequests, &RateLimitError{}},
+		{"InvalidServerAPIKey", http.StatusUnauthorized, &InvalidServerAPIKeyError{}},
+		{"MessageNotFound", http.StatusNotFound, &MessageNotFoundError{}},
+		{"UnableToSendMessage", http.StatusInternalServerError, &UnableToSendMessageError{}},
+		{"NoContent", http.StatusUnprocessableEntity, &NoContentError{}},
+		{"NoRecipients", http.StatusUnprocessableEntity, &NoRecipientsError{}},
+		{"AttachmentMissingName", http.StatusUnprocessableEntity, &AttachmentMissingNameError{}},
+		{"SomeUnrecognizedCode", http.StatusInternalServerError, &APIError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			r := &http.Response{StatusCode: tt.httpStatus}
+			data := map[string]interface{}{"code": tt.code, "message": "boom"}
+
+			err := newTypedError(r, data)
+
+			if got, want := fmt.Sprintf("%T", err), fmt.Sprintf("%T", tt.want); got != want {
+				t.Fatalf("newTypedError(%q) = %s, want %s", tt.code, got, want)
+			}
+		})
+	}
+}
+
+func TestNewTypedErrorFallsBackToRateLimitOn429(t *testing.T) {
+	r := &http.Response{StatusCode: http.StatusTooManyRequests}
+	data := map[string]interface{}{"code": "SomeUnrecognizedCode", "message": "slow down"}
+
+	err := newTypedError(r, data)
+
+	var rateLimit *RateLimitError
+	if !errors.As(err, &rateLimit) {
+		t.Fatalf("expected a *RateLimitError for an unrecognized code with HTTP 429, got %T", err)
+	}
+}
+
+// TestErrorResponseUnwrap exercises the full chain a caller actually sees:
+// CheckResponse wraps the typed error in an *ErrorResponse, and errors.As must be
+// able to unwrap through it to recover the concrete type.
+func TestErrorResponseUnwrap(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": statusError,
+			"data":   map[string]interface{}{"code": "ValidationError", "message": "To is required"},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "key")
+	_, _, err := c.Send.Send(context.Background(), &SendRequest{From: "b@example.com"})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("expected an *ErrorResponse, got %T", err)
+	}
+
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected errors.As to unwrap *ErrorResponse into a *ValidationError, got %T", err)
+	}
+	if validation.Message != "To is required" {
+		t.Fatalf("Message = %q, want %q", validation.Message, "To is required")
+	}
+}
@@ -0,0 +1,259 @@
+package postal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// MessageBuilder assembles a compliant RFC2822 message: headers, a
+// multipart/alternative plain+HTML body, inline images referenced by Content-ID,
+// and mixed attachments.
+type MessageBuilder struct {
+	From      string
+	Sender    string
+	To        []string
+	CC        []string
+	BCC       []string
+	ReplyTo   string
+	Subject   string
+	PlainBody string
+	HTMLBody  string
+	// Headers are additional headers merged into the message, e.g. "X-Custom-Header".
+	Headers map[string]string
+	// Attachments may be regular (mixed) or inline; see Attachment.Inline and
+	// Attachment.ContentID for embedding images in HTMLBody via "cid:<ContentID>".
+	Attachments []*Attachment
+}
+
+// NewMessageBuilder returns an empty MessageBuilder ready to be populated.
+func NewMessageBuilder() *MessageBuilder {
+	return &MessageBuilder{Headers: map[string]string{}}
+}
+
+// Build assembles the message into a complete RFC2822 payload. Attachment parts
+// are base64-encoded inside it, but the payload as a whole is not; see
+// ToSendRAWRequest for that.
+func (b *MessageBuilder) Build() ([]byte, error) {
+	if len(b.To)+len(b.CC)+len(b.BCC) == 0 {
+		return nil, &NoRecipientsError{&APIError{Message: "message has no recipients"}}
+	}
+	for _, a := range b.Attachments {
+		if err := a.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	var body bytes.Buffer
+	contentType, err := b.writeBody(&body)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	writeHeader(&msg, "From", b.From)
+	if b.Sender != "" {
+		writeHeader(&msg, "Sender", b.Sender)
+	}
+	if len(b.To) > 0 {
+		writeHeader(&msg, "To", strings.Join(b.To, ", "))
+	}
+	if len(b.CC) > 0 {
+		writeHeader(&msg, "Cc", strings.Join(b.CC, ", "))
+	}
+	if b.ReplyTo != "" {
+		writeHeader(&msg, "Reply-To", b.ReplyTo)
+	}
+	writeHeader(&msg, "Subject", mime.QEncoding.Encode("UTF-8", b.Subject))
+	writeHeader(&msg, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&msg, "MIME-Version", "1.0")
+	for k, v := range b.Headers {
+		writeHeader(&msg, k, v)
+	}
+	writeHeader(&msg, "Content-Type", contentType)
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
+
+// ToSendRAWRequest builds the message and base64-encodes it into a SendRAWRequest,
+// filling MailFrom from Sender (or From) and RcptTo from To, CC and BCC.
+func (b *MessageBuilder) ToSendRAWRequest() (*SendRAWRequest, error) {
+	data, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	mailFrom := b.Sender
+	if mailFrom == "" {
+		mailFrom = b.From
+	}
+
+	rcptTo := make([]string, 0, len(b.To)+len(b.CC)+len(b.BCC))
+	rcptTo = append(rcptTo, b.To...)
+	rcptTo = append(rcptTo, b.CC...)
+	rcptTo = append(rcptTo, b.BCC...)
+
+	return &SendRAWRequest{
+		MailFrom: mailFrom,
+		RcptTo:   rcptTo,
+		Data:     base64.StdEncoding.EncodeToString(data),
+	}, nil
+}
+
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+}
+
+// mimePart is a single part of a multipart body, ready to hand to a multipart.Writer.
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// writeBody assembles the plain/HTML/inline/attachment parts into the minimal
+// multipart nesting the message actually needs, writing the result to w and
+// returning the Content-Type header describing it.
+func (b *MessageBuilder) writeBody(w *bytes.Buffer) (string, error) {
+	content, contentType, err := b.alternativeContent()
+	if err != nil {
+		return "", err
+	}
+
+	var inline, mixed []*Attachment
+	for _, a := range b.Attachments {
+		if a.Inline {
+			inline = append(inline, a)
+		} else {
+			mixed = append(mixed, a)
+		}
+	}
+
+	if len(inline) > 0 {
+		parts := append([]mimePart{{header: contentTypeHeader(contentType), body: content}}, attachmentParts(inline)...)
+		content, contentType, err = writeMultipart("related", parts)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(mixed) > 0 {
+		parts := append([]mimePart{{header: contentTypeHeader(contentType), body: content}}, attachmentParts(mixed)...)
+		content, contentType, err = writeMultipart("mixed", parts)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := w.Write(content); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+// alternativeContent builds the text/plain and text/html parts of the message,
+// wrapping them in a multipart/alternative only when both are present.
+func (b *MessageBuilder) alternativeContent() ([]byte, string, error) {
+	var parts []mimePart
+	if b.PlainBody != "" {
+		parts = append(parts, mimePart{
+			header: textproto.MIMEHeader{
+				"Content-Type":              {"text/plain; charset=utf-8"},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			},
+			body: quotedPrintableEncode([]byte(b.PlainBody)),
+		})
+	}
+	if b.HTMLBody != "" {
+		parts = append(parts, mimePart{
+			header: textproto.MIMEHeader{
+				"Content-Type":              {"text/html; charset=utf-8"},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			},
+			body: quotedPrintableEncode([]byte(b.HTMLBody)),
+		})
+	}
+
+	switch len(parts) {
+	case 0:
+		return nil, "", &NoContentError{&APIError{Message: "message has neither a plain nor an HTML body"}}
+	case 1:
+		return parts[0].body, parts[0].header.Get("Content-Type"), nil
+	default:
+		return writeMultipart("alternative", parts)
+	}
+}
+
+func attachmentParts(attachments []*Attachment) []mimePart {
+	parts := make([]mimePart, 0, len(attachments))
+	for _, a := range attachments {
+		disposition := "attachment"
+		if a.Inline {
+			disposition = "inline"
+		}
+		header := textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", a.ContentType, a.Name)},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {fmt.Sprintf("%s; filename=%q", disposition, a.Name)},
+		}
+		if a.ContentID != "" {
+			header.Set("Content-ID", "<"+a.ContentID+">")
+		}
+		parts = append(parts, mimePart{header: header, body: base64EncodeWrapped(a.Data)})
+	}
+	return parts
+}
+
+func contentTypeHeader(contentType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{"Content-Type": {contentType}}
+}
+
+// writeMultipart writes parts as a multipart/<subtype> body and returns the
+// resulting bytes alongside the Content-Type header describing them.
+func writeMultipart(subtype string, parts []mimePart) ([]byte, string, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		pw, err := mw.CreatePart(p.header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := pw.Write(p.body); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), fmt.Sprintf("multipart/%s; boundary=%q", subtype, mw.Boundary()), nil
+}
+
+func quotedPrintableEncode(data []byte) []byte {
+	var buf bytes.Buffer
+	qw := quotedprintable.NewWriter(&buf)
+	qw.Write(data)
+	qw.Close()
+	return buf.Bytes()
+}
+
+func base64EncodeWrapped(data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var buf bytes.Buffer
+	const lineLength = 76
+	for i := 0; i < len(encoded); i += lineLength {
+		end := i + lineLength
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		buf.WriteString(encoded[i:end])
+		buf.WriteString("\r\n")
+	}
+	return buf.Bytes()
+}
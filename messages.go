@@ -2,6 +2,7 @@ package postal
 
 import (
 	"context"
+	"errors"
 	"net/http"
 )
 
@@ -9,6 +10,7 @@ const (
 	messagesBasePath = "/api/v1/messages"
 	detailsPath      = messagesBasePath + "/message"
 	deliveriesPath   = messagesBasePath + "/deliveries"
+	listPath         = messagesBasePath
 )
 
 // MessagesService is an interface for interfacing with the message
@@ -17,6 +19,7 @@ const (
 type MessagesService interface {
 	GetMessage(context.Context, *GetMessageRequest) (*MessageDetails, *Response, error)
 	GetDeliveries(context.Context, *GetDeliveriesRequest) (*[]MessageDeliveries, *Response, error)
+	List(context.Context, *ListMessagesRequest) *MessagesIterator
 }
 
 // MessagesServiceOp handles communication with the message related methods of the Postal API.
@@ -129,3 +132,144 @@ func (mvc *MessagesServiceOp) GetDeliveries(ctx context.Context, getRequest *Get
 	}
 	return root.Deliveries, resp, nil
 }
+
+// ListMessagesRequest represents a request to the Postal API to enumerate messages.
+type ListMessagesRequest struct {
+	// Scope restricts the search to "incoming" or "outgoing" messages
+	Scope string `json:"scope,omitempty"`
+	// Tag restricts the search to messages sent with this tag
+	Tag string `json:"tag,omitempty"`
+	// From restricts the search to messages received after this time
+	From string `json:"from,omitempty"`
+	// To restricts the search to messages received before this time
+	To string `json:"to,omitempty"`
+	// Limit is the maximum number of messages to return in a single page
+	Limit int `json:"limit,omitempty"`
+	// PageToken is an opaque cursor returned by a previous request's Paging
+	PageToken string `json:"page_token,omitempty"`
+}
+
+// MessageSummary is a single entry in a message listing.
+type MessageSummary struct {
+	ID        int     `json:"id"`
+	Token     string  `json:"token"`
+	Subject   string  `json:"subject"`
+	RcptTo    string  `json:"rcpt_to"`
+	Status    string  `json:"status"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// Paging describes the cursor links returned alongside a paginated response.
+type Paging struct {
+	First    string `json:"first"`
+	Previous string `json:"previous"`
+	Next     string `json:"next"`
+	Last     string `json:"last"`
+}
+
+type messagesListData struct {
+	Messages []MessageSummary `json:"messages"`
+	Paging   Paging           `json:"paging"`
+}
+
+type messagesListRoot struct {
+	Data *messagesListData `json:"data"`
+}
+
+// List returns a MessagesIterator that enumerates messages matching listRequest.
+// No request is made until the iterator's Next, First, Previous or Last method is called.
+func (mvc *MessagesServiceOp) List(ctx context.Context, listRequest *ListMessagesRequest) *MessagesIterator {
+	if listRequest == nil {
+		listRequest = &ListMessagesRequest{}
+	}
+	return &MessagesIterator{client: mvc.client, request: *listRequest}
+}
+
+// MessagesIterator walks a paginated message listing, following the Paging
+// cursor returned by the Postal API one page at a time.
+type MessagesIterator struct {
+	client   *Client
+	request  ListMessagesRequest
+	paging   Paging
+	response *Response
+	err      error
+	started  bool
+}
+
+// Next decodes the next page of messages into dst, returning false once the
+// listing is exhausted or a request fails. Call Err to distinguish the two
+// cases; a failed Next does not latch, so calling Next again retries the same
+// page.
+func (it *MessagesIterator) Next(ctx context.Context, dst *[]MessageSummary) bool {
+	if !it.started {
+		if !it.fetch(ctx, &it.request, dst) {
+			return false
+		}
+		it.started = true
+		return true
+	}
+	if it.paging.Next == "" {
+		return false
+	}
+	return it.fetch(ctx, &ListMessagesRequest{PageToken: it.paging.Next}, dst)
+}
+
+// First decodes the first page of messages into dst.
+func (it *MessagesIterator) First(ctx context.Context, dst *[]MessageSummary) bool {
+	it.started = true
+	if it.paging.First == "" {
+		return it.fetch(ctx, &it.request, dst)
+	}
+	return it.fetch(ctx, &ListMessagesRequest{PageToken: it.paging.First}, dst)
+}
+
+// Previous decodes the previous page of messages into dst.
+func (it *MessagesIterator) Previous(ctx context.Context, dst *[]MessageSummary) bool {
+	if it.paging.Previous == "" {
+		return false
+	}
+	return it.fetch(ctx, &ListMessagesRequest{PageToken: it.paging.Previous}, dst)
+}
+
+// Last decodes the last page of messages into dst.
+func (it *MessagesIterator) Last(ctx context.Context, dst *[]MessageSummary) bool {
+	if it.paging.Last == "" {
+		return false
+	}
+	return it.fetch(ctx, &ListMessagesRequest{PageToken: it.paging.Last}, dst)
+}
+
+// Err returns the error from the iterator's most recent request, or nil if that
+// request succeeded.
+func (it *MessagesIterator) Err() error {
+	return it.err
+}
+
+// Response returns the Response from the most recent request made by the iterator.
+func (it *MessagesIterator) Response() *Response {
+	return it.response
+}
+
+func (it *MessagesIterator) fetch(ctx context.Context, listRequest *ListMessagesRequest, dst *[]MessageSummary) bool {
+	req, err := it.client.NewRequest(ctx, http.MethodPost, listPath, listRequest)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	root := new(messagesListRoot)
+	resp, err := it.client.Do(ctx, req, root)
+	it.response = resp
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if root.Data == nil {
+		it.err = errors.New("postal: messages listing response had no data")
+		return false
+	}
+
+	it.err = nil
+	it.paging = root.Data.Paging
+	*dst = root.Data.Messages
+	return true
+}
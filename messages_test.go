@@ -0,0 +1,73 @@
+package postal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMessagesIteratorRecoversFromTransientError(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"messages": []map[string]interface{}{{"id": 1}},
+				"paging":   map[string]interface{}{},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "key")
+	it := c.Messages.List(context.Background(), nil)
+
+	var dst []MessageSummary
+	if it.Next(context.Background(), &dst) {
+		t.Fatalf("expected first Next to fail on the transient 500")
+	}
+	if it.Err() == nil {
+		t.Fatalf("expected Err() to report the transient failure")
+	}
+
+	if !it.Next(context.Background(), &dst) {
+		t.Fatalf("expected second Next to succeed, got err: %v", it.Err())
+	}
+	if it.Err() != nil {
+		t.Fatalf("expected Err() to be cleared after a successful retry, got %v", it.Err())
+	}
+	if len(dst) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(dst))
+	}
+	if calls != 2 {
+		t.Fatalf("expected the second Next to re-issue the same request, got %d calls", calls)
+	}
+}
+
+func TestMessagesIteratorNilData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   nil,
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "key")
+	it := c.Messages.List(context.Background(), nil)
+
+	var dst []MessageSummary
+	if it.Next(context.Background(), &dst) {
+		t.Fatalf("expected Next to fail on a nil data field")
+	}
+	if it.Err() == nil {
+		t.Fatalf("expected Err() to report the nil data field")
+	}
+}
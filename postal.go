@@ -3,21 +3,31 @@ package postal
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
+	mathrand "math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 const (
-	libraryVersion = "0.1.0"
+	libraryVersion = "0.2.0"
 	userAgent      = "gopostal/" + libraryVersion
 	mediaType      = "application/json"
 
 	statusSuccess        = "success"
 	statusParameterError = "parameter-error"
 	statusError          = "error"
+
+	// defaultMaxRetries is used when Client.MaxRetries is left unset.
+	defaultMaxRetries = 3
+
+	baseRetryDelay = 200 * time.Millisecond
+	maxRetryDelay  = 5 * time.Second
 )
 
 // Client manages communication with the Postal API.
@@ -37,6 +47,19 @@ type Client struct {
 	// User agent for client
 	UserAgent string
 
+	// MaxRetries is the number of times a retry-enabled request (see WithIdempotencyKey)
+	// is resent after a network error, a 429, or a 5xx response. Defaults to defaultMaxRetries
+	// when left at zero.
+	MaxRetries int
+
+	// Transport, when set, overrides how SendingService.SendBuilt delivers a message
+	// instead of the default HTTP API.
+	Transport Transport
+
+	// SMTPFallback, when set, is used by SendingService.SendBuilt to retry delivery
+	// over SMTP if the HTTP API request fails.
+	SMTPFallback *SMTPTransport
+
 	// Optional function called after every successful request made to the Postal API
 	onRequestCompleted RequestCompletionCallback
 
@@ -73,6 +96,10 @@ type ErrorResponse struct {
 
 	// Error data
 	Data interface{} `json:"data"`
+
+	// err is the concrete, typed error (e.g. *RateLimitError) this response wraps.
+	// Use errors.As to recover it.
+	err error
 }
 
 // NewClient returns a new Postal API client, using the given
@@ -111,10 +138,63 @@ func (c *Client) SetApiKey(akey string) {
 	c.ApiKey = akey
 }
 
+// RequestOption configures a per-call request option such as an idempotency key.
+type RequestOption func(*requestOptions)
+
+// requestOptions holds the resolved state of the RequestOptions passed to a single call.
+type requestOptions struct {
+	idempotencyKey    string
+	idempotencyExpiry time.Duration
+	retry             bool
+}
+
+// WithIdempotencyKey attaches an Idempotency-Key header to the request, so the Postal
+// server can deduplicate retried deliveries, and enables automatic retry on network
+// errors, 429s and 5xxs for the call it is passed to.
+func WithIdempotencyKey(key string) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotencyKey = key
+		o.retry = true
+	}
+}
+
+// WithIdempotencyExpiry sets how long the Postal server should remember the idempotency
+// key for, sent as the x-idempotency-expiration header in seconds. Implies the same
+// automatic retry behavior as WithIdempotencyKey.
+func WithIdempotencyExpiry(expiry time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.idempotencyExpiry = expiry
+		o.retry = true
+	}
+}
+
+// resolveRequestOptions applies opts in order and returns the resulting requestOptions.
+func resolveRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// newIdempotencyKey generates a random UUIDv4 for use as an Idempotency-Key.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
 // NewRequest creates an API request. A relative URL can be provided in urlStr, which will be resolved to the
 // BaseURL of the Client. Relative URLS should always be specified without a preceding slash. If specified, the
-// value pointed to by body is JSON encoded and included in as the request body.
-func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body interface{}) (*http.Request, error) {
+// value pointed to by body is JSON encoded and included in as the request body. Pass WithIdempotencyKey or
+// WithIdempotencyExpiry to set the corresponding headers; a random key is generated when only an expiry is
+// given. The options only set headers here: Do sends a request once, and the automatic retries are made
+// by SendingService.Send and SendRAW when they are passed the same options.
+func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body interface{}, opts ...RequestOption) (*http.Request, error) {
 	u, err := c.BaseURL.Parse(urlStr)
 	if err != nil {
 		return nil, err
@@ -151,6 +231,20 @@ func (c *Client) NewRequest(ctx context.Context, method, urlStr string, body int
 	req.Header.Set("X-Server-API-Key", c.ApiKey)
 	req.Header.Set("User-Agent", c.UserAgent)
 
+	o := resolveRequestOptions(opts)
+	if o.retry && o.idempotencyKey == "" {
+		o.idempotencyKey, err = newIdempotencyKey()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if o.idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", o.idempotencyKey)
+	}
+	if o.idempotencyExpiry > 0 {
+		req.Header.Set("x-idempotency-expiration", strconv.Itoa(int(o.idempotencyExpiry.Seconds())))
+	}
+
 	return req, nil
 }
 
@@ -199,6 +293,74 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*Res
 	return response, err
 }
 
+// doWithRetry behaves like Do, but when opts enable retry it resends req on network
+// errors, 429s and 5xxs using exponential backoff with jitter, honoring any Retry-After
+// header, up to Client.MaxRetries times after the first attempt.
+func (c *Client) doWithRetry(ctx context.Context, req *http.Request, v interface{}, opts ...RequestOption) (*Response, error) {
+	o := resolveRequestOptions(opts)
+	if !o.retry {
+		return c.Do(ctx, req, v)
+	}
+
+	maxRetries := c.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	var resp *Response
+	var err error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, gerr := req.GetBody()
+			if gerr != nil {
+				return resp, gerr
+			}
+			req.Body = body
+		}
+
+		resp, err = c.Do(ctx, req, v)
+		if attempt >= maxRetries || !shouldRetry(resp, err) {
+			return resp, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return resp, ctx.Err()
+		case <-time.After(retryDelay(attempt, resp)):
+		}
+	}
+}
+
+// shouldRetry reports whether a response/error pair from a retry-enabled request
+// warrants another attempt: a network error with no response, a 429, or a 5xx.
+func shouldRetry(resp *Response, err error) bool {
+	if resp == nil || resp.Response == nil {
+		return err != nil
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryDelay computes the backoff before the given retry attempt (0-indexed), honoring
+// a Retry-After header when present and otherwise using exponential backoff with jitter.
+func retryDelay(attempt int, resp *Response) time.Duration {
+	if resp != nil && resp.Response != nil {
+		if ra := resp.Header.Get("Retry-After"); ra != "" {
+			if secs, err := strconv.Atoi(ra); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+
+	delay := baseRetryDelay * time.Duration(1<<uint(attempt))
+	if delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	return delay/2 + time.Duration(mathrand.Int63n(int64(delay/2)+1))
+}
+
 // DoRequest submits an HTTP request.
 func DoRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
 	return DoRequestWithClient(ctx, http.DefaultClient, req)
@@ -219,6 +381,12 @@ func (r *ErrorResponse) Error() string {
 		r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, r.Data)
 }
 
+// Unwrap exposes the concrete, typed error (e.g. *RateLimitError, *ValidationError)
+// this response wraps, so callers can use errors.As/errors.Is against it.
+func (r *ErrorResponse) Unwrap() error {
+	return r.err
+}
+
 func CheckResponse(r *http.Response) ([]byte, error) {
 	var response Response
 	errorResponse := &ErrorResponse{Response: r}
@@ -230,7 +398,9 @@ func CheckResponse(r *http.Response) ([]byte, error) {
 			return nil, errorResponse
 		}
 		if response.Status != statusSuccess {
-			errorResponse.Data = response.Data.(map[string]interface{})["message"]
+			m, _ := response.Data.(map[string]interface{})
+			errorResponse.Data = m["message"]
+			errorResponse.err = newTypedError(r, m)
 			return nil, errorResponse
 		}
 	}
@@ -0,0 +1,247 @@
+// Package postaltest provides an in-process mock Postal server for unit-testing
+// code that sends mail or looks up messages through a *postal.Client.
+package postaltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/Pacerino/postal-go"
+)
+
+// RecordedMessage is a single send request captured by a Server, either through
+// the Send or the SendRAW endpoint.
+type RecordedMessage struct {
+	// Path is the endpoint the request was made to, e.g. "/api/v1/send/message".
+	Path string
+	// Send is set when the request came in through the Send endpoint.
+	Send *postal.SendRequest
+	// SendRAW is set when the request came in through the SendRAW endpoint.
+	SendRAW *postal.SendRAWRequest
+}
+
+type queuedError struct {
+	status  int
+	code    string
+	message string
+}
+
+// Server is a mock Postal API server. Create one with NewServer.
+type Server struct {
+	// Client is pre-configured to point at the mock server with a matching API key.
+	Client *postal.Client
+	// APIKey is the key the mock server expects in the X-Server-API-Key header.
+	APIKey string
+
+	t       *testing.T
+	httpSrv *httptest.Server
+
+	mu          sync.Mutex
+	sent        []RecordedMessage
+	queuedErrs  []queuedError
+	queuedSends []*postal.SendResponse
+	messages    map[int]*postal.MessageDetails
+	deliveries  map[int][]postal.MessageDeliveries
+}
+
+// NewServer starts a mock Postal API server and returns a Server wrapping it. The
+// server, and the *postal.Client pointed at it, are torn down automatically via
+// t.Cleanup.
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+
+	s := &Server{
+		APIKey:     "postaltest-api-key",
+		t:          t,
+		messages:   make(map[int]*postal.MessageDetails),
+		deliveries: make(map[int][]postal.MessageDeliveries),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/send/message", s.handleSend)
+	mux.HandleFunc("/api/v1/send/raw", s.handleSendRAW)
+	mux.HandleFunc("/api/v1/messages/message", s.handleGetMessage)
+	mux.HandleFunc("/api/v1/messages/deliveries", s.handleGetDeliveries)
+
+	s.httpSrv = httptest.NewServer(mux)
+	t.Cleanup(s.httpSrv.Close)
+
+	s.Client = postal.NewClient(s.httpSrv.URL, s.APIKey)
+
+	return s
+}
+
+// Sent returns every SendRequest/SendRAWRequest received so far, in request order.
+func (s *Server) Sent() []RecordedMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]RecordedMessage, len(s.sent))
+	copy(out, s.sent)
+	return out
+}
+
+// EnqueueError makes the next Send or SendRAW request fail with the given HTTP
+// status and Postal data.code/data.message. Passing http.StatusTooManyRequests
+// also sets a Retry-After header, for exercising Client retry behavior.
+func (s *Server) EnqueueError(status int, code, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queuedErrs = append(s.queuedErrs, queuedError{status: status, code: code, message: message})
+}
+
+// EnqueueSend makes the next Send or SendRAW request succeed with resp instead of
+// the server's default generated response.
+func (s *Server) EnqueueSend(resp *postal.SendResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queuedSends = append(s.queuedSends, resp)
+}
+
+// SeedMessage makes details available from GetMessage for the given ID.
+func (s *Server) SeedMessage(id int, details *postal.MessageDetails) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[id] = details
+}
+
+// SeedDeliveries makes deliveries available from GetDeliveries for the given message ID.
+func (s *Server) SeedDeliveries(id int, deliveries []postal.MessageDeliveries) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries[id] = deliveries
+}
+
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAPIKey(w, r) {
+		return
+	}
+	var req postal.SendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidJSON", err.Error())
+		return
+	}
+	s.recordSent(RecordedMessage{Path: r.URL.Path, Send: &req})
+	s.respondSend(w)
+}
+
+func (s *Server) handleSendRAW(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAPIKey(w, r) {
+		return
+	}
+	var req postal.SendRAWRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidJSON", err.Error())
+		return
+	}
+	s.recordSent(RecordedMessage{Path: r.URL.Path, SendRAW: &req})
+	s.respondSend(w)
+}
+
+func (s *Server) handleGetMessage(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAPIKey(w, r) {
+		return
+	}
+	var req postal.GetMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidJSON", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	details, ok := s.messages[req.ID]
+	s.mu.Unlock()
+	if !ok {
+		s.writeError(w, http.StatusNotFound, "MessageNotFound", fmt.Sprintf("message %d not found", req.ID))
+		return
+	}
+	writeEnvelope(w, http.StatusOK, statusSuccess, details)
+}
+
+func (s *Server) handleGetDeliveries(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAPIKey(w, r) {
+		return
+	}
+	var req postal.GetDeliveriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "InvalidJSON", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	deliveries := s.deliveries[req.ID]
+	s.mu.Unlock()
+	writeEnvelope(w, http.StatusOK, statusSuccess, deliveries)
+}
+
+func (s *Server) respondSend(w http.ResponseWriter) {
+	s.mu.Lock()
+	var qErr *queuedError
+	if len(s.queuedErrs) > 0 {
+		e := s.queuedErrs[0]
+		s.queuedErrs = s.queuedErrs[1:]
+		qErr = &e
+	}
+	var resp *postal.SendResponse
+	if qErr == nil {
+		if len(s.queuedSends) > 0 {
+			resp = s.queuedSends[0]
+			s.queuedSends = s.queuedSends[1:]
+		}
+	}
+	s.mu.Unlock()
+
+	if qErr != nil {
+		if qErr.status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		s.writeError(w, qErr.status, qErr.code, qErr.message)
+		return
+	}
+
+	if resp == nil {
+		resp = &postal.SendResponse{MessageID: "mock-message-id@postaltest"}
+	}
+	writeEnvelope(w, http.StatusOK, statusSuccess, resp)
+}
+
+func (s *Server) checkAPIKey(w http.ResponseWriter, r *http.Request) bool {
+	if key := r.Header.Get("X-Server-API-Key"); key != s.APIKey {
+		s.t.Errorf("postaltest: request to %s had X-Server-API-Key %q, want %q", r.URL.Path, key, s.APIKey)
+		s.writeError(w, http.StatusForbidden, "InvalidServerAPIKey", "invalid API key")
+		return false
+	}
+	return true
+}
+
+func (s *Server) recordSent(m RecordedMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, m)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, code, message string) {
+	if status == 0 {
+		status = http.StatusOK
+	}
+	writeEnvelope(w, status, statusError, map[string]interface{}{"code": code, "message": message})
+}
+
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+)
+
+func writeEnvelope(w http.ResponseWriter, httpStatus int, apiStatus string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": apiStatus,
+		"time":   0.01,
+		"flags":  map[string]interface{}{},
+		"data":   data,
+	})
+}
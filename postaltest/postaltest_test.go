@@ -0,0 +1,90 @@
+package postaltest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	postal "github.com/Pacerino/postal-go"
+)
+
+func TestServerRecordsSendWithAttachment(t *testing.T) {
+	srv := NewServer(t)
+
+	att, err := postal.NewAttachmentFromReader("report.csv", strings.NewReader("a,b,c"))
+	if err != nil {
+		t.Fatalf("NewAttachmentFromReader: %v", err)
+	}
+
+	_, _, err = srv.Client.Send.Send(context.Background(), &postal.SendRequest{
+		To:          []string{"to@example.com"},
+		From:        "from@example.com",
+		Subject:     "hello",
+		PlainBody:   "hi",
+		Attachments: []*postal.Attachment{att},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	sent := srv.Sent()
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 recorded message, got %d", len(sent))
+	}
+	if sent[0].Send == nil {
+		t.Fatalf("expected recorded message to be a Send request")
+	}
+	if len(sent[0].Send.Attachments) != 1 {
+		t.Fatalf("expected 1 recorded attachment, got %d", len(sent[0].Send.Attachments))
+	}
+
+	got := sent[0].Send.Attachments[0]
+	if got.ContentType != att.ContentType {
+		t.Errorf("ContentType = %q, want %q", got.ContentType, att.ContentType)
+	}
+	if string(got.Data) != string(att.Data) {
+		t.Errorf("Data = %q, want %q", got.Data, att.Data)
+	}
+}
+
+func TestServerEnqueueError(t *testing.T) {
+	srv := NewServer(t)
+	srv.EnqueueError(http.StatusTooManyRequests, "RateLimited", "slow down")
+
+	_, _, err := srv.Client.Send.Send(context.Background(), &postal.SendRequest{
+		To:   []string{"to@example.com"},
+		From: "from@example.com",
+	})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	var rateLimit *postal.RateLimitError
+	if !errors.As(err, &rateLimit) {
+		t.Fatalf("expected a *postal.RateLimitError, got %v (%T)", err, err)
+	}
+}
+
+func TestServerSeedMessageAndDeliveries(t *testing.T) {
+	srv := NewServer(t)
+	srv.SeedMessage(42, &postal.MessageDetails{ID: 42, Token: "tok"})
+	srv.SeedDeliveries(42, []postal.MessageDeliveries{{ID: 1, Status: "Sent"}})
+
+	details, _, err := srv.Client.Messages.GetMessage(context.Background(), &postal.GetMessageRequest{ID: 42})
+	if err != nil {
+		t.Fatalf("GetMessage: %v", err)
+	}
+	if details.Token != "tok" {
+		t.Fatalf("Token = %q, want %q", details.Token, "tok")
+	}
+
+	deliveries, _, err := srv.Client.Messages.GetDeliveries(context.Background(), &postal.GetDeliveriesRequest{ID: 42})
+	if err != nil {
+		t.Fatalf("GetDeliveries: %v", err)
+	}
+	if len(*deliveries) != 1 || (*deliveries)[0].Status != "Sent" {
+		t.Fatalf("unexpected deliveries: %+v", deliveries)
+	}
+}
@@ -0,0 +1,78 @@
+package postal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDoWithRetryRecoversAfterTransient5xx(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   map[string]interface{}{"message_id": "abc"},
+		})
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "key")
+	resp, _, err := c.Send.Send(context.Background(),
+		&SendRequest{To: []string{"a@example.com"}, From: "b@example.com"},
+		WithIdempotencyKey("test-key"))
+	if err != nil {
+		t.Fatalf("expected eventual success, got err: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+	if resp.MessageID != "abc" {
+		t.Fatalf("MessageID = %q, want %q", resp.MessageID, "abc")
+	}
+}
+
+func TestDoWithRetryStopsAtMaxRetries(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "key")
+	c.MaxRetries = 2
+	_, _, err := c.Send.Send(context.Background(),
+		&SendRequest{To: []string{"a@example.com"}, From: "b@example.com"},
+		WithIdempotencyKey("test-key"))
+	if err == nil {
+		t.Fatalf("expected an error after exhausting retries")
+	}
+	if calls != 3 {
+		t.Fatalf("expected 1 initial attempt + 2 retries = 3 calls, got %d", calls)
+	}
+}
+
+func TestDoWithRetryDisabledWithoutIdempotencyKey(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, "key")
+	_, _, err := c.Send.Send(context.Background(), &SendRequest{To: []string{"a@example.com"}, From: "b@example.com"})
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected no retries without an idempotency option, got %d calls", calls)
+	}
+}
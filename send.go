@@ -15,8 +15,9 @@ const (
 // endpoints of the Postal API.
 // See: https://apiv1.postalserver.io/controllers/send.html
 type SendingService interface {
-	Send(context.Context, *SendRequest) (*SendResponse, *Response, error)
-	SendRAW(context.Context, *SendRAWRequest) (*SendResponse, *Response, error)
+	Send(context.Context, *SendRequest, ...RequestOption) (*SendResponse, *Response, error)
+	SendRAW(context.Context, *SendRAWRequest, ...RequestOption) (*SendResponse, *Response, error)
+	SendBuilt(context.Context, *MessageBuilder, ...RequestOption) (*SendResponse, *Response, error)
 }
 
 // SendingServiceeOp handles communication with the sending related methods of the Postal API.
@@ -48,8 +49,11 @@ type SendRequest struct {
 	PlainBody string `json:"plain_body"`
 	// The HTML body of the e-mail
 	HTMLBody string `json:"html_body"`
-	// An array of attachments for this e-mail
-	Attachments interface{} `json:"attachments"`
+	// An array of attachments for this e-mail.
+	//
+	// This was an untyped interface{} prior to v0.2.0; build attachments with
+	// NewAttachmentFromFile, NewAttachmentFromReader or NewInlineAttachment.
+	Attachments []*Attachment `json:"attachments,omitempty"`
 	// A hash of additional headers
 	Headers map[string]interface{} `json:"headers"`
 	// Is this message a bounce?
@@ -81,28 +85,65 @@ type SendResponse struct {
 	} `json:"messages"`
 }
 
-// Send a message through the Postal API
-func (svc *SendingServiceeOp) Send(ctx context.Context, sendRequest *SendRequest) (*SendResponse, *Response, error) {
-	req, err := svc.client.NewRequest(ctx, http.MethodPost, sendPath, sendRequest)
+// Send a message through the Postal API. Pass WithIdempotencyKey (and optionally
+// WithIdempotencyExpiry) to make the send safe to automatically retry on network
+// errors, 429s and 5xxs.
+func (svc *SendingServiceeOp) Send(ctx context.Context, sendRequest *SendRequest, opts ...RequestOption) (*SendResponse, *Response, error) {
+	for _, a := range sendRequest.Attachments {
+		if err := a.Validate(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	req, err := svc.client.NewRequest(ctx, http.MethodPost, sendPath, sendRequest, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
 	root := new(sendRoot)
-	resp, err := svc.client.Do(ctx, req, root)
+	resp, err := svc.client.doWithRetry(ctx, req, root, opts...)
 	if err != nil {
 		return nil, resp, err
 	}
 	return root.Hash, resp, nil
 }
 
-// SendRAW a message through the Postal API using a raw RFC2822 message
-func (svc *SendingServiceeOp) SendRAW(ctx context.Context, sendRAWRequest *SendRAWRequest) (*SendResponse, *Response, error) {
-	req, err := svc.client.NewRequest(ctx, http.MethodPost, sendRawPath, sendRAWRequest)
+// SendBuilt delivers a message assembled with a MessageBuilder. It uses the
+// client's HTTP API by default, an explicit Client.Transport override when set,
+// and falls back to Client.SMTPFallback when the HTTP request fails and a
+// fallback is configured.
+func (svc *SendingServiceeOp) SendBuilt(ctx context.Context, b *MessageBuilder, opts ...RequestOption) (*SendResponse, *Response, error) {
+	if svc.client.Transport != nil {
+		resp, err := svc.client.Transport.Send(ctx, b)
+		return resp, nil, err
+	}
+
+	raw, err := b.ToSendRAWRequest()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, httpResp, err := svc.SendRAW(ctx, raw, opts...)
+	if err == nil || svc.client.SMTPFallback == nil {
+		return resp, httpResp, err
+	}
+
+	fallbackResp, ferr := svc.client.SMTPFallback.Send(ctx, b)
+	if ferr != nil {
+		return resp, httpResp, err
+	}
+	return fallbackResp, nil, nil
+}
+
+// SendRAW a message through the Postal API using a raw RFC2822 message. Pass
+// WithIdempotencyKey (and optionally WithIdempotencyExpiry) to make the send safe to
+// automatically retry on network errors, 429s and 5xxs.
+func (svc *SendingServiceeOp) SendRAW(ctx context.Context, sendRAWRequest *SendRAWRequest, opts ...RequestOption) (*SendResponse, *Response, error) {
+	req, err := svc.client.NewRequest(ctx, http.MethodPost, sendRawPath, sendRAWRequest, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
 	root := new(sendRoot)
-	resp, err := svc.client.Do(ctx, req, root)
+	resp, err := svc.client.doWithRetry(ctx, req, root, opts...)
 	if err != nil {
 		return nil, resp, err
 	}
@@ -0,0 +1,183 @@
+package postal
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"regexp"
+)
+
+// Transport delivers a built message, either through the Postal HTTP API or
+// directly over SMTP.
+type Transport interface {
+	Send(ctx context.Context, b *MessageBuilder) (*SendResponse, error)
+}
+
+// httpTransport delivers a MessageBuilder through SendingService.SendRAW.
+type httpTransport struct {
+	service SendingService
+}
+
+// NewHTTPTransport returns a Transport that delivers a MessageBuilder through the
+// Postal HTTP API's SendRAW endpoint, the same path SendingService.SendBuilt uses
+// by default. It is mainly useful for assigning Client.Transport explicitly, e.g.
+// to pin delivery to the HTTP API even when an SMTPFallback is configured.
+func NewHTTPTransport(service SendingService) Transport {
+	return &httpTransport{service: service}
+}
+
+// Send implements Transport.
+func (t *httpTransport) Send(ctx context.Context, b *MessageBuilder) (*SendResponse, error) {
+	raw, err := b.ToSendRAWRequest()
+	if err != nil {
+		return nil, err
+	}
+	resp, _, err := t.service.SendRAW(ctx, raw)
+	return resp, err
+}
+
+// messageIDPattern extracts a queued message ID from an SMTP server's final DATA
+// response, e.g. "250 2.0.0 Ok: queued as 2C5F51234A".
+var messageIDPattern = regexp.MustCompile(`(?i)queued as (\S+)`)
+
+// SMTPTransport delivers a MessageBuilder by dialing a Postal server's SMTP
+// submission port directly, for use as a fallback when the HTTP API is unreachable.
+type SMTPTransport struct {
+	// Addr is the "host:port" of the SMTP submission endpoint.
+	Addr string
+	// Username and Password authenticate with PLAIN auth, after STARTTLS when
+	// the server offers it. Leave both empty to skip authentication.
+	Username string
+	Password string
+	// TLSConfig overrides the STARTTLS configuration. Defaults to verifying the
+	// certificate against Addr's host.
+	TLSConfig *tls.Config
+}
+
+// NewSMTPTransport returns an SMTPTransport that authenticates with username and
+// password against the SMTP submission endpoint at addr.
+func NewSMTPTransport(addr, username, password string) *SMTPTransport {
+	return &SMTPTransport{Addr: addr, Username: username, Password: password}
+}
+
+// Send implements Transport, delivering b over SMTP with PLAIN auth. The
+// connection is upgraded with STARTTLS when the server advertises it; otherwise
+// the session continues unencrypted.
+func (t *SMTPTransport) Send(ctx context.Context, b *MessageBuilder) (*SendResponse, error) {
+	raw, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	mailFrom := b.Sender
+	if mailFrom == "" {
+		mailFrom = b.From
+	}
+	rcptTo := make([]string, 0, len(b.To)+len(b.CC)+len(b.BCC))
+	rcptTo = append(rcptTo, b.To...)
+	rcptTo = append(rcptTo, b.CC...)
+	rcptTo = append(rcptTo, b.BCC...)
+
+	host, _, err := net.SplitHostPort(t.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("postal: invalid SMTP address %q: %w", t.Addr, err)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// smtp.Dial has no context-aware variant; since conn is already open, closing
+	// it on ctx.Done unblocks any in-flight read/write so cancellation still works.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		tlsConfig := t.TLSConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{ServerName: host}
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	if t.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", t.Username, t.Password, host)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Mail(mailFrom); err != nil {
+		return nil, err
+	}
+	for _, rcpt := range rcptTo {
+		if err := c.Rcpt(rcpt); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := sendData(c, raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Quit(); err != nil {
+		return nil, err
+	}
+
+	sendResp := &SendResponse{}
+	if m := messageIDPattern.FindStringSubmatch(response); m != nil {
+		sendResp.MessageID = m[1]
+	}
+	return sendResp, nil
+}
+
+// sendData issues the DATA command and returns the server's final response text,
+// which net/smtp.Client.Data's Close method otherwise discards.
+func sendData(c *smtp.Client, raw []byte) (string, error) {
+	id, err := c.Text.Cmd("DATA")
+	if err != nil {
+		return "", err
+	}
+	c.Text.StartResponse(id)
+	_, _, err = c.Text.ReadResponse(354)
+	c.Text.EndResponse(id)
+	if err != nil {
+		return "", err
+	}
+
+	dw := c.Text.DotWriter()
+	if _, err := dw.Write(raw); err != nil {
+		return "", err
+	}
+	if err := dw.Close(); err != nil {
+		return "", err
+	}
+
+	id = c.Text.Next()
+	c.Text.StartResponse(id)
+	defer c.Text.EndResponse(id)
+	_, message, err := c.Text.ReadResponse(250)
+	return message, err
+}
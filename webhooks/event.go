@@ -0,0 +1,168 @@
+// Package webhooks implements the receiving side of Postal's webhook callbacks:
+// signature verification and typed dispatch of MessageSent, MessageDelivered,
+// MessageDeliveryFailed, MessageBounced, MessageHeld, MessageLoaded and
+// MessageClicked events.
+// See: https://apiv1.postalserver.io/developer/webhooks.html
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EventType identifies the kind of event a webhook payload carries.
+type EventType string
+
+const (
+	EventMessageSent           EventType = "MessageSent"
+	EventMessageDelivered      EventType = "MessageDelivered"
+	EventMessageDeliveryFailed EventType = "MessageDeliveryFailed"
+	EventMessageBounced        EventType = "MessageBounced"
+	EventMessageHeld           EventType = "MessageHeld"
+	EventMessageLoaded         EventType = "MessageLoaded"
+	EventMessageClicked        EventType = "MessageClicked"
+)
+
+// Event is implemented by every typed webhook payload.
+type Event interface {
+	EventType() EventType
+}
+
+// Message is the subset of message fields Postal includes on most events.
+type Message struct {
+	ID        int     `json:"id"`
+	Token     string  `json:"token"`
+	MessageID string  `json:"message_id"`
+	Direction string  `json:"direction"`
+	MailFrom  string  `json:"mail_from"`
+	RcptTo    string  `json:"rcpt_to"`
+	Subject   string  `json:"subject"`
+	Tag       string  `json:"tag,omitempty"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// MessageSentEvent is sent once Postal has accepted a message for delivery.
+type MessageSentEvent struct {
+	Message   Message `json:"message"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// EventType implements Event.
+func (e *MessageSentEvent) EventType() EventType { return EventMessageSent }
+
+// MessageDeliveredEvent is sent once a message has been delivered to its destination server.
+type MessageDeliveredEvent struct {
+	Message     Message `json:"message"`
+	Details     string  `json:"details"`
+	Output      string  `json:"output"`
+	SentWithSSL bool    `json:"sent_with_ssl"`
+	Timestamp   float64 `json:"timestamp"`
+}
+
+// EventType implements Event.
+func (e *MessageDeliveredEvent) EventType() EventType { return EventMessageDelivered }
+
+// MessageDeliveryFailedEvent is sent when Postal gives up trying to deliver a message.
+type MessageDeliveryFailedEvent struct {
+	Message   Message `json:"message"`
+	Status    string  `json:"status"`
+	Details   string  `json:"details"`
+	Output    string  `json:"output"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// EventType implements Event.
+func (e *MessageDeliveryFailedEvent) EventType() EventType { return EventMessageDeliveryFailed }
+
+// MessageBouncedEvent is sent when a delivery attempt generates a bounce message.
+type MessageBouncedEvent struct {
+	Message   Message `json:"message"`
+	Bounce    Message `json:"bounce"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// EventType implements Event.
+func (e *MessageBouncedEvent) EventType() EventType { return EventMessageBounced }
+
+// MessageHeldEvent is sent when Postal holds a message instead of delivering it.
+type MessageHeldEvent struct {
+	Message   Message `json:"message"`
+	Reason    string  `json:"reason"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// EventType implements Event.
+func (e *MessageHeldEvent) EventType() EventType { return EventMessageHeld }
+
+// MessageLoadedEvent is sent when a tracking pixel in a message is loaded.
+type MessageLoadedEvent struct {
+	Message   Message `json:"message"`
+	IPAddress string  `json:"ip_address"`
+	UserAgent string  `json:"user_agent"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// EventType implements Event.
+func (e *MessageLoadedEvent) EventType() EventType { return EventMessageLoaded }
+
+// MessageClickedEvent is sent when a tracked link in a message is clicked.
+type MessageClickedEvent struct {
+	Message   Message `json:"message"`
+	URL       string  `json:"url"`
+	IPAddress string  `json:"ip_address"`
+	UserAgent string  `json:"user_agent"`
+	Timestamp float64 `json:"timestamp"`
+}
+
+// EventType implements Event.
+func (e *MessageClickedEvent) EventType() EventType { return EventMessageClicked }
+
+// envelope mirrors the top-level shape Postal wraps every webhook payload in.
+type envelope struct {
+	Event     EventType       `json:"event"`
+	Timestamp int64           `json:"timestamp"`
+	UUID      string          `json:"uuid"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// ParseEvent decodes a raw webhook request body into its typed Event, for callers
+// who already route and verify the request themselves.
+func ParseEvent(body []byte) (Event, error) {
+	event, _, err := parseEnvelope(body)
+	return event, err
+}
+
+func parseEnvelope(body []byte) (Event, int64, error) {
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, 0, fmt.Errorf("webhooks: decoding envelope: %w", err)
+	}
+
+	var event Event
+	switch env.Event {
+	case EventMessageSent:
+		event = new(MessageSentEvent)
+	case EventMessageDelivered:
+		event = new(MessageDeliveredEvent)
+	case EventMessageDeliveryFailed:
+		event = new(MessageDeliveryFailedEvent)
+	case EventMessageBounced:
+		event = new(MessageBouncedEvent)
+	case EventMessageHeld:
+		event = new(MessageHeldEvent)
+	case EventMessageLoaded:
+		event = new(MessageLoadedEvent)
+	case EventMessageClicked:
+		event = new(MessageClickedEvent)
+	default:
+		return nil, 0, fmt.Errorf("webhooks: unknown event type %q", env.Event)
+	}
+
+	if len(env.Payload) > 0 {
+		if err := json.Unmarshal(env.Payload, event); err != nil {
+			return nil, 0, fmt.Errorf("webhooks: decoding %s payload: %w", env.Event, err)
+		}
+	}
+
+	return event, env.Timestamp, nil
+}
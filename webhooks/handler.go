@@ -0,0 +1,161 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SignatureHeader is the HTTP header Postal sends the RSA-SHA1 request signature in.
+const SignatureHeader = "X-Postal-Signature"
+
+// eventHandlerFunc is the internal, untyped form every registered handler is
+// normalized to before dispatch.
+type eventHandlerFunc func(ctx context.Context, event Event) error
+
+// Handler is an http.Handler that verifies and dispatches Postal webhook requests
+// to typed callbacks registered through its On* methods.
+type Handler struct {
+	// Verifier validates the X-Postal-Signature header. May be left nil to skip
+	// verification, e.g. when the caller verifies the request some other way.
+	Verifier *Verifier
+
+	// MaxAge rejects events whose timestamp is further than this from the current
+	// time, in the past or the future, guarding against replayed requests. Zero
+	// disables the check.
+	MaxAge time.Duration
+
+	mu       sync.RWMutex
+	handlers map[EventType][]eventHandlerFunc
+}
+
+// NewHandler returns a Handler that verifies incoming requests with verifier.
+// Pass a nil verifier to skip signature verification.
+func NewHandler(verifier *Verifier) *Handler {
+	return &Handler{
+		Verifier: verifier,
+		handlers: make(map[EventType][]eventHandlerFunc),
+	}
+}
+
+// OnEvent registers fn to be called for every webhook of the given type. It is the
+// untyped fallback behind the typed OnMessageSent, OnMessageBounced, etc. helpers.
+func (h *Handler) OnEvent(t EventType, fn func(ctx context.Context, event Event) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[t] = append(h.handlers[t], fn)
+}
+
+// OnMessageSent registers fn to be called for every MessageSent event.
+func (h *Handler) OnMessageSent(fn func(ctx context.Context, event *MessageSentEvent) error) {
+	h.OnEvent(EventMessageSent, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(*MessageSentEvent))
+	})
+}
+
+// OnMessageDelivered registers fn to be called for every MessageDelivered event.
+func (h *Handler) OnMessageDelivered(fn func(ctx context.Context, event *MessageDeliveredEvent) error) {
+	h.OnEvent(EventMessageDelivered, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(*MessageDeliveredEvent))
+	})
+}
+
+// OnMessageDeliveryFailed registers fn to be called for every MessageDeliveryFailed event.
+func (h *Handler) OnMessageDeliveryFailed(fn func(ctx context.Context, event *MessageDeliveryFailedEvent) error) {
+	h.OnEvent(EventMessageDeliveryFailed, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(*MessageDeliveryFailedEvent))
+	})
+}
+
+// OnMessageBounced registers fn to be called for every MessageBounced event.
+func (h *Handler) OnMessageBounced(fn func(ctx context.Context, event *MessageBouncedEvent) error) {
+	h.OnEvent(EventMessageBounced, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(*MessageBouncedEvent))
+	})
+}
+
+// OnMessageHeld registers fn to be called for every MessageHeld event.
+func (h *Handler) OnMessageHeld(fn func(ctx context.Context, event *MessageHeldEvent) error) {
+	h.OnEvent(EventMessageHeld, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(*MessageHeldEvent))
+	})
+}
+
+// OnMessageLoaded registers fn to be called for every MessageLoaded event.
+func (h *Handler) OnMessageLoaded(fn func(ctx context.Context, event *MessageLoadedEvent) error) {
+	h.OnEvent(EventMessageLoaded, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(*MessageLoadedEvent))
+	})
+}
+
+// OnMessageClicked registers fn to be called for every MessageClicked event.
+func (h *Handler) OnMessageClicked(fn func(ctx context.Context, event *MessageClickedEvent) error) {
+	h.OnEvent(EventMessageClicked, func(ctx context.Context, event Event) error {
+		return fn(ctx, event.(*MessageClickedEvent))
+	})
+}
+
+// ServeHTTP implements http.Handler. It reads and verifies the request body,
+// decodes the event envelope, enforces MaxAge when set, and dispatches the event
+// to every registered handler, responding 500 if any of them return an error so
+// Postal retries the delivery.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	if h.Verifier != nil {
+		sig := r.Header.Get(SignatureHeader)
+		if sig == "" {
+			http.Error(w, "missing "+SignatureHeader+" header", http.StatusUnauthorized)
+			return
+		}
+		if err := h.Verifier.Verify(body, sig); err != nil {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	event, timestamp, err := parseEnvelope(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if h.MaxAge > 0 {
+		age := time.Since(time.Unix(timestamp, 0))
+		if age < 0 {
+			age = -age
+		}
+		if age > h.MaxAge {
+			http.Error(w, "webhooks: event timestamp outside of allowed age", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	if err := h.dispatch(r.Context(), event); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) dispatch(ctx context.Context, event Event) error {
+	h.mu.RLock()
+	fns := append([]eventHandlerFunc(nil), h.handlers[event.EventType()]...)
+	h.mu.RUnlock()
+
+	var errs []error
+	for _, fn := range fns {
+		if err := fn(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,107 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func messageSentBody(timestamp int64) []byte {
+	return []byte(fmt.Sprintf(
+		`{"event":"MessageSent","timestamp":%d,"uuid":"test-uuid","payload":{"message":{"id":1},"timestamp":%d}}`,
+		timestamp, timestamp))
+}
+
+func serveRequest(h *Handler, body []byte, sig string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(string(body)))
+	if sig != "" {
+		req.Header.Set(SignatureHeader, sig)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerAcceptsValidSignature(t *testing.T) {
+	v, sign := generateTestKey(t)
+	h := NewHandler(v)
+
+	var got *MessageSentEvent
+	h.OnMessageSent(func(ctx context.Context, event *MessageSentEvent) error {
+		got = event
+		return nil
+	})
+
+	body := messageSentBody(time.Now().Unix())
+	rec := serveRequest(h, body, sign(body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if got == nil {
+		t.Fatalf("expected OnMessageSent callback to run")
+	}
+}
+
+func TestHandlerRejectsTamperedBody(t *testing.T) {
+	v, sign := generateTestKey(t)
+	h := NewHandler(v)
+
+	sig := sign(messageSentBody(time.Now().Unix()))
+	rec := serveRequest(h, messageSentBody(time.Now().Unix()+1), sig)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandlerRejectsMissingSignatureHeader(t *testing.T) {
+	v, _ := generateTestKey(t)
+	h := NewHandler(v)
+
+	rec := serveRequest(h, messageSentBody(time.Now().Unix()), "")
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandlerMaxAgeRejectsStaleEvent(t *testing.T) {
+	v, sign := generateTestKey(t)
+	h := NewHandler(v)
+	h.MaxAge = time.Minute
+
+	body := messageSentBody(time.Now().Add(-time.Hour).Unix())
+	rec := serveRequest(h, body, sign(body))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandlerMaxAgeAcceptsFreshEvent(t *testing.T) {
+	v, sign := generateTestKey(t)
+	h := NewHandler(v)
+	h.MaxAge = time.Minute
+
+	body := messageSentBody(time.Now().Unix())
+	rec := serveRequest(h, body, sign(body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+}
+
+func TestHandlerNilVerifierSkipsVerification(t *testing.T) {
+	h := NewHandler(nil)
+
+	rec := serveRequest(h, messageSentBody(time.Now().Unix()), "")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body: %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+}
@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// Verifier validates the X-Postal-Signature header Postal sends with every webhook
+// request, an RSA-SHA1 signature of the raw request body, against the server's
+// configured public key.
+type Verifier struct {
+	publicKey *rsa.PublicKey
+}
+
+// NewVerifier parses a PEM encoded RSA public key, as shown on the webhook signing
+// page of a Postal server's settings, and returns a Verifier for it.
+func NewVerifier(pemPublicKey []byte) (*Verifier, error) {
+	block, _ := pem.Decode(pemPublicKey)
+	if block == nil {
+		return nil, errors.New("webhooks: no PEM data found in public key")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		rsaPub, pkcs1Err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if pkcs1Err != nil {
+			return nil, fmt.Errorf("webhooks: parsing public key: %w", err)
+		}
+		return &Verifier{publicKey: rsaPub}, nil
+	}
+
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("webhooks: public key is not an RSA key")
+	}
+	return &Verifier{publicKey: rsaPub}, nil
+}
+
+// Verify checks signatureBase64, the value of an X-Postal-Signature header, against
+// the RSA-SHA1 signature of body. It returns nil only when the signature is valid.
+func (v *Verifier) Verify(body []byte, signatureBase64 string) error {
+	if v.publicKey == nil {
+		return errors.New("webhooks: verifier has no public key; use NewVerifier to construct one")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return fmt.Errorf("webhooks: decoding signature: %w", err)
+	}
+
+	digest := sha1.Sum(body)
+	if err := rsa.VerifyPKCS1v15(v.publicKey, crypto.SHA1, digest[:], sig); err != nil {
+		return fmt.Errorf("webhooks: signature verification failed: %w", err)
+	}
+	return nil
+}
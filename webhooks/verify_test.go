@@ -0,0 +1,81 @@
+package webhooks
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+)
+
+// generateTestKey returns a Verifier for a freshly generated RSA key plus a sign
+// function producing the base64 X-Postal-Signature value for a body.
+func generateTestKey(t *testing.T) (*Verifier, func(body []byte) string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPKIXPublicKey: %v", err)
+	}
+	pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	v, err := NewVerifier(pemBytes)
+	if err != nil {
+		t.Fatalf("NewVerifier: %v", err)
+	}
+
+	sign := func(body []byte) string {
+		digest := sha1.Sum(body)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+		if err != nil {
+			t.Fatalf("SignPKCS1v15: %v", err)
+		}
+		return base64.StdEncoding.EncodeToString(sig)
+	}
+
+	return v, sign
+}
+
+func TestVerifyAcceptsValidSignature(t *testing.T) {
+	v, sign := generateTestKey(t)
+	body := []byte(`{"event":"MessageSent"}`)
+
+	if err := v.Verify(body, sign(body)); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	v, sign := generateTestKey(t)
+	body := []byte(`{"event":"MessageSent"}`)
+	sig := sign(body)
+
+	if err := v.Verify([]byte(`{"event":"MessageBounced"}`), sig); err == nil {
+		t.Fatalf("expected Verify to reject a body that doesn't match the signature")
+	}
+}
+
+func TestVerifyRejectsMalformedSignature(t *testing.T) {
+	v, _ := generateTestKey(t)
+
+	if err := v.Verify([]byte("body"), "not-base64!!"); err == nil {
+		t.Fatalf("expected Verify to reject a non-base64 signature")
+	}
+}
+
+func TestVerifyNilPublicKeyReturnsError(t *testing.T) {
+	v := &Verifier{}
+
+	err := v.Verify([]byte("body"), "c2ln")
+	if err == nil {
+		t.Fatalf("expected Verify to return an error for a zero-valued Verifier")
+	}
+}